@@ -0,0 +1,127 @@
+// Package api exposes the mesh's hostname registry as a JSON/msgpack
+// REST API: listing, fetching, claiming and tombstoning names.
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Lassulus/data-mesher/mesh"
+	"github.com/Lassulus/data-mesher/store"
+)
+
+// Server serves the /names REST surface backed by a store.Store, and
+// pushes an immediate gossip round after accepting a change.
+type Server struct {
+	Store    *store.Store
+	Gossiper *mesh.Gossiper
+}
+
+// NewServer returns a Server ready to Register its routes.
+func NewServer(db *store.Store, gossiper *mesh.Gossiper) *Server {
+	return &Server{Store: db, Gossiper: gossiper}
+}
+
+// Register wires the /names routes onto mux.
+func (s *Server) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/names", s.handleNames)
+	mux.HandleFunc("/names/", s.handleName)
+}
+
+func (s *Server) handleNames(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	all := s.Store.GetAll().Hostnames
+	live := make([]mesh.Hostname, 0, len(all))
+	for _, h := range all {
+		if !h.Deleted {
+			live = append(live, h)
+		}
+	}
+	writeBody(w, r, http.StatusOK, live)
+}
+
+func (s *Server) handleName(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/names/")
+	if name == "" {
+		writeError(w, r, http.StatusBadRequest, "missing name")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.getName(w, r, name)
+	case http.MethodPost:
+		s.claimName(w, r, name)
+	case http.MethodDelete:
+		s.deleteName(w, r, name)
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) getName(w http.ResponseWriter, r *http.Request, name string) {
+	h, ok := s.Store.Get(name)
+	if !ok || h.Deleted {
+		writeError(w, r, http.StatusNotFound, "name not found")
+		return
+	}
+	writeBody(w, r, http.StatusOK, h)
+}
+
+func (s *Server) claimName(w http.ResponseWriter, r *http.Request, name string) {
+	var h mesh.Hostname
+	if err := readBody(r, &h); err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if h.Name != name {
+		writeError(w, r, http.StatusBadRequest, "body name does not match URL")
+		return
+	}
+	h.Deleted = false
+
+	s.apply(w, r, h)
+}
+
+func (s *Server) deleteName(w http.ResponseWriter, r *http.Request, name string) {
+	var tombstone mesh.Hostname
+	if err := readBody(r, &tombstone); err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	if tombstone.Name != name {
+		writeError(w, r, http.StatusBadRequest, "body name does not match URL")
+		return
+	}
+	tombstone.Deleted = true
+
+	s.apply(w, r, tombstone)
+}
+
+// apply runs a claim or tombstone through the store and, if accepted,
+// pushes it out to peers right away.
+func (s *Server) apply(w http.ResponseWriter, r *http.Request, h mesh.Hostname) {
+	ok, err := s.Store.Put(h, r.RemoteAddr)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		writeError(w, r, http.StatusConflict, "rejected: invalid signature, stale, or name held by another key")
+		return
+	}
+
+	if s.Gossiper != nil {
+		go s.Gossiper.PushNow()
+	}
+
+	if h.Deleted {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeBody(w, r, http.StatusOK, h)
+}
@@ -0,0 +1,54 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const keyFileName = "ed25519.key"
+
+// KeyPair is this node's persistent signing identity.
+type KeyPair struct {
+	Public  ed25519.PublicKey
+	Private ed25519.PrivateKey
+}
+
+// PublicHex returns the hex-encoded public key, as used on the wire and
+// in trusted-keys files.
+func (k *KeyPair) PublicHex() string {
+	return hex.EncodeToString(k.Public)
+}
+
+// LoadOrGenerate loads the node's Ed25519 keypair from keyDir, generating
+// and persisting a new one on first run.
+func LoadOrGenerate(keyDir string) (*KeyPair, error) {
+	if err := os.MkdirAll(keyDir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating key dir: %w", err)
+	}
+
+	path := filepath.Join(keyDir, keyFileName)
+	raw, err := os.ReadFile(path)
+	if err == nil {
+		if len(raw) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("key file %s has unexpected size %d", path, len(raw))
+		}
+		priv := ed25519.PrivateKey(raw)
+		return &KeyPair{Public: priv.Public().(ed25519.PublicKey), Private: priv}, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading key file: %w", err)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating key: %w", err)
+	}
+	if err := os.WriteFile(path, priv, 0o600); err != nil {
+		return nil, fmt.Errorf("writing key file: %w", err)
+	}
+	return &KeyPair{Public: pub, Private: priv}, nil
+}
@@ -0,0 +1,40 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Sign produces a hex-encoded Ed25519 signature over (name, t,
+// deleted), binding a hostname claim or tombstone to this node's
+// identity.
+func (k *KeyPair) Sign(name string, t time.Time, deleted bool) string {
+	return hex.EncodeToString(ed25519.Sign(k.Private, signedMessage(name, t, deleted)))
+}
+
+// Verify checks that sigHex is a valid Ed25519 signature over (name, t,
+// deleted) produced by the holder of pubkeyHex, and that t does not lie
+// further than maxSkew in the future.
+func Verify(pubkeyHex, name string, t time.Time, deleted bool, sigHex string, maxSkew time.Duration) bool {
+	if t.After(time.Now().Add(maxSkew)) {
+		return false
+	}
+
+	pubkey, err := hex.DecodeString(pubkeyHex)
+	if err != nil || len(pubkey) != ed25519.PublicKeySize {
+		return false
+	}
+
+	sig, err := hex.DecodeString(sigHex)
+	if err != nil {
+		return false
+	}
+
+	return ed25519.Verify(ed25519.PublicKey(pubkey), signedMessage(name, t, deleted), sig)
+}
+
+func signedMessage(name string, t time.Time, deleted bool) []byte {
+	return []byte(fmt.Sprintf("%s|%d|%t", name, t.UnixNano(), deleted))
+}
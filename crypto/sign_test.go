@@ -0,0 +1,71 @@
+package crypto
+
+import (
+	"testing"
+	"time"
+)
+
+func mustKeyPair(t *testing.T) *KeyPair {
+	t.Helper()
+	kp, err := LoadOrGenerate(t.TempDir())
+	if err != nil {
+		t.Fatalf("generating keypair: %s", err)
+	}
+	return kp
+}
+
+func TestSignAndVerify(t *testing.T) {
+	kp := mustKeyPair(t)
+	other := mustKeyPair(t)
+	now := time.Now()
+	validSig := kp.Sign("alice", now, false)
+
+	tests := []struct {
+		name    string
+		pubkey  string
+		claim   string
+		at      time.Time
+		deleted bool
+		sig     string
+		want    bool
+	}{
+		{"valid signature", kp.PublicHex(), "alice", now, false, validSig, true},
+		{"wrong name", kp.PublicHex(), "bob", now, false, validSig, false},
+		{"wrong deleted flag", kp.PublicHex(), "alice", now, true, validSig, false},
+		{"wrong key", other.PublicHex(), "alice", now, false, validSig, false},
+		{"malformed signature", kp.PublicHex(), "alice", now, false, "not-hex", false},
+		{"malformed pubkey", "not-hex", "alice", now, false, validSig, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.pubkey, tt.claim, tt.at, tt.deleted, tt.sig, time.Minute); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsFutureSkew(t *testing.T) {
+	kp := mustKeyPair(t)
+
+	tests := []struct {
+		name    string
+		skewAdd time.Duration
+		maxSkew time.Duration
+		want    bool
+	}{
+		{"within skew", 30 * time.Second, time.Minute, true},
+		{"beyond skew", 2 * time.Minute, time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := time.Now().Add(tt.skewAdd)
+			sig := kp.Sign("alice", at, false)
+			if got := Verify(kp.PublicHex(), "alice", at, false, sig, tt.maxSkew); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
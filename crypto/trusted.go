@@ -0,0 +1,65 @@
+package crypto
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TrustedKeys pins hostnames to the public key allowed to claim them,
+// so a name already owned by one key can't later be taken over by
+// another.
+type TrustedKeys struct {
+	pins map[string]string // name -> hex pubkey
+}
+
+// LoadTrustedKeys reads a file of "name pubkeyhex" lines, one pin per
+// line, blank lines and "#" comments ignored. A missing path yields an
+// empty, fully permissive TrustedKeys.
+func LoadTrustedKeys(path string) (*TrustedKeys, error) {
+	t := &TrustedKeys{pins: make(map[string]string)}
+	if path == "" {
+		return t, nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening trusted-keys file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid trusted-keys line: %q", line)
+		}
+		t.pins[fields[0]] = fields[1]
+	}
+	return t, scanner.Err()
+}
+
+// Allowed reports whether pubkeyHex may claim name: true if the name is
+// unpinned, or if it's pinned to exactly this key.
+func (t *TrustedKeys) Allowed(name, pubkeyHex string) bool {
+	pinned, ok := t.pins[name]
+	if !ok {
+		return true
+	}
+	return pinned == pubkeyHex
+}
+
+// Pinned reports whether name has an explicit pin, i.e. an operator
+// has deliberately fixed which key may hold it.
+func (t *TrustedKeys) Pinned(name string) bool {
+	_, ok := t.pins[name]
+	return ok
+}
@@ -0,0 +1,63 @@
+package crypto
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTrustedKeysFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "trusted-keys")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("writing trusted-keys file: %s", err)
+	}
+	return path
+}
+
+func TestTrustedKeysAllowed(t *testing.T) {
+	path := writeTrustedKeysFile(t, "alice aaaa\n# a comment\n\nbob bbbb\n")
+
+	trusted, err := LoadTrustedKeys(path)
+	if err != nil {
+		t.Fatalf("LoadTrustedKeys: %s", err)
+	}
+
+	tests := []struct {
+		name   string
+		claim  string
+		pubkey string
+		want   bool
+	}{
+		{"pinned key matches", "alice", "aaaa", true},
+		{"pinned key mismatch", "alice", "cccc", false},
+		{"unpinned name always allowed", "carol", "dddd", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trusted.Allowed(tt.claim, tt.pubkey); got != tt.want {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !trusted.Pinned("alice") {
+		t.Error("expected alice to be pinned")
+	}
+	if trusted.Pinned("carol") {
+		t.Error("expected carol to be unpinned")
+	}
+}
+
+func TestLoadTrustedKeysMissingFileIsPermissive(t *testing.T) {
+	trusted, err := LoadTrustedKeys(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err != nil {
+		t.Fatalf("LoadTrustedKeys: %s", err)
+	}
+	if !trusted.Allowed("anything", "whatever") {
+		t.Error("a missing trusted-keys file should be fully permissive")
+	}
+	if trusted.Pinned("anything") {
+		t.Error("a missing trusted-keys file should have no pins")
+	}
+}
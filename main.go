@@ -4,39 +4,134 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strings"
 	"time"
-)
 
-type hostname struct {
-	name      string
-	signature string
-	time      time.Time
-}
+	"github.com/rs/zerolog"
+	"gopkg.in/natefinch/lumberjack.v2"
 
-type hostdata struct {
-	timestamp time.Time
-	hostnames []hostname
-}
+	"github.com/Lassulus/data-mesher/api"
+	"github.com/Lassulus/data-mesher/crypto"
+	"github.com/Lassulus/data-mesher/mesh"
+	"github.com/Lassulus/data-mesher/store"
+)
 
 func main() {
 	port := flag.Int("port", 7331, "port to listen on")
+	peers := flag.String("peers", "", "comma-separated list of host:port peers to gossip with")
+	gossipInterval := flag.Duration("gossip-interval", 10*time.Second, "how often to gossip with peers")
+	keyDir := flag.String("key-dir", "./keys", "directory holding this node's Ed25519 keypair")
+	trustedKeysPath := flag.String("trusted-keys", "", "file pinning hostnames to the pubkey allowed to claim them")
+	maxSkew := flag.Duration("max-skew", time.Minute, "reject claims whose time is further than this in the future")
+	dataDir := flag.String("data-dir", "./data", "directory holding the persistent hostname log and snapshots")
+	snapshotEvery := flag.Int("snapshot-every", 100, "write a fresh snapshot and truncate the log every N accepted updates")
+	fsync := flag.Bool("fsync", false, "fsync the update log after every accepted record")
+	logFile := flag.String("log-file", "", "file to write logs to (empty logs to stderr)")
+	logMaxSizeMB := flag.Int("log-max-size-mb", 100, "maximum size in megabytes of a log file before it's rotated")
+	logMaxBackups := flag.Int("log-max-backups", 5, "maximum number of rotated log files to keep")
+	logMaxAgeDays := flag.Int("log-max-age-days", 28, "maximum age in days to retain rotated log files")
+	logLevel := flag.String("log-level", "info", "minimum log level (debug, info, warn, error)")
+	tlsHosts := flag.String("tls-hosts", "", "comma-separated hostnames to obtain Let's Encrypt certificates for")
+	certCacheDir := flag.String("cert-cache-dir", "./certs", "directory to cache ACME certificates in")
+	clientCA := flag.String("client-ca", "", "PEM file of CA certs peers must present a client cert from (enables mutual TLS)")
+	insecure := flag.Bool("insecure", false, "serve plain HTTP instead of TLS, for local testing")
 	flag.Parse()
 
-	http.HandleFunc("/", getData)
+	level, err := zerolog.ParseLevel(*logLevel)
+	if err != nil {
+		fmt.Printf("invalid log level: %s\n", err)
+		os.Exit(1)
+	}
 
-	listenHost := fmt.Sprintf(":%d", *port)
+	var logOutput io.Writer = os.Stderr
+	if *logFile != "" {
+		logOutput = &lumberjack.Logger{
+			Filename:   *logFile,
+			MaxSize:    *logMaxSizeMB,
+			MaxBackups: *logMaxBackups,
+			MaxAge:     *logMaxAgeDays,
+		}
+	}
+	logger := zerolog.New(logOutput).Level(level).With().Timestamp().Logger()
 
-	err := http.ListenAndServe(listenHost, nil)
+	keys, err := crypto.LoadOrGenerate(*keyDir)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("loading keypair")
+	}
+
+	trusted, err := crypto.LoadTrustedKeys(*trustedKeysPath)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("loading trusted-keys file")
+	}
+
+	verify := func(h mesh.Hostname, existing *mesh.Hostname, peer string) bool {
+		start := time.Now()
+		ok := crypto.Verify(h.Pubkey, h.Name, h.Time, h.Deleted, h.Signature, *maxSkew) && trusted.Allowed(h.Name, h.Pubkey)
+		if ok && existing != nil && existing.Pubkey != h.Pubkey && !trusted.Pinned(h.Name) {
+			ok = false
+		}
+
+		event := logger.Debug()
+		if !ok {
+			event = logger.Warn()
+		}
+		event.
+			Str("peer", peer).
+			Str("name", h.Name).
+			Bool("sig_ok", ok).
+			Dur("latency_ms", time.Since(start)).
+			Msg("verified hostname claim")
+		return ok
+	}
+
+	db, err := store.Open(*dataDir, *snapshotEvery, *fsync)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("opening store")
+	}
+	db.SetVerify(verify)
+	db.SetLogger(logger)
+	defer db.Close()
+
+	gossiper := mesh.NewGossiper(db, splitCSV(*peers), *gossipInterval, 3)
+	gossiper.SetLogger(logger)
+
+	stop := make(chan struct{})
+	go gossiper.Run(stop)
+	defer close(stop)
+
+	http.HandleFunc("/gossip", gossiper.Handler)
+	http.HandleFunc("/pubkey", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, keys.PublicHex())
+	})
+	api.NewServer(db, gossiper).Register(http.DefaultServeMux)
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", *port),
+		Handler: loggingMiddleware(logger, http.DefaultServeMux),
+	}
+
+	cfg := tlsConfig{
+		Hosts:        splitCSV(*tlsHosts),
+		CertCacheDir: *certCacheDir,
+		ClientCA:     *clientCA,
+		Insecure:     *insecure,
+	}
+
+	err = serve(srv, cfg, logger)
 	if errors.Is(err, http.ErrServerClosed) {
-		fmt.Printf("server closed\n")
+		logger.Info().Msg("server closed")
 	} else if err != nil {
-		fmt.Printf("error starting server: %s\n", err)
-		os.Exit(1)
+		logger.Fatal().Err(err).Msg("starting server")
 	}
 }
 
-func getData(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprintf(w, "Hello, %s!", r.URL.Path[1:])
+// splitCSV turns a comma-separated flag value into a string slice.
+func splitCSV(csv string) []string {
+	if csv == "" {
+		return nil
+	}
+	return strings.Split(csv, ",")
 }
@@ -0,0 +1,17 @@
+package mesh
+
+// VerifyFunc reports whether a Hostname record's signature is valid and
+// its claim is allowed to replace existing, the record currently held
+// for the same name (nil if the name is unclaimed). peer identifies
+// who submitted the record (a gossip peer address or an HTTP client),
+// so verification failures can be logged with their source.
+type VerifyFunc func(h Hostname, existing *Hostname, peer string) bool
+
+// Backend is the state a Gossiper merges incoming records into and
+// reads from to send to peers. store.Store implements it with on-disk
+// persistence; GetAll must be a pure read with no side effects, since
+// it runs on every gossip round and every incoming push.
+type Backend interface {
+	GetAll() HostData
+	Merge(data HostData, peer string) []Hostname
+}
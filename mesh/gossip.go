@@ -0,0 +1,134 @@
+package mesh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Gossiper periodically pushes this node's HostData to a random subset
+// of known peers and merges whatever they send back into Backend.
+type Gossiper struct {
+	Backend  Backend
+	Peers    []string
+	Interval time.Duration
+	Fanout   int
+	Logger   zerolog.Logger
+
+	client *http.Client
+}
+
+// NewGossiper builds a Gossiper that contacts up to fanout peers per
+// round. A fanout <= 0 means "all peers".
+func NewGossiper(backend Backend, peers []string, interval time.Duration, fanout int) *Gossiper {
+	return &Gossiper{
+		Backend:  backend,
+		Peers:    peers,
+		Interval: interval,
+		Fanout:   fanout,
+		Logger:   zerolog.Nop(),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SetLogger installs the logger used to report gossip exchanges.
+func (g *Gossiper) SetLogger(logger zerolog.Logger) {
+	g.Logger = logger
+}
+
+// Run drives gossip rounds on Interval until stop is closed.
+func (g *Gossiper) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(g.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			g.round()
+		}
+	}
+}
+
+// PushNow gossips the current state to peers immediately, outside the
+// normal interval. Callers use it right after a local claim or
+// tombstone so the change propagates without waiting for the next
+// tick.
+func (g *Gossiper) PushNow() {
+	g.round()
+}
+
+func (g *Gossiper) round() {
+	for _, peer := range g.pickPeers() {
+		_ = g.exchange(peer)
+	}
+}
+
+func (g *Gossiper) pickPeers() []string {
+	if g.Fanout <= 0 || g.Fanout >= len(g.Peers) {
+		return g.Peers
+	}
+	shuffled := make([]string, len(g.Peers))
+	copy(shuffled, g.Peers)
+	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	return shuffled[:g.Fanout]
+}
+
+func (g *Gossiper) exchange(peer string) error {
+	start := time.Now()
+
+	body, err := json.Marshal(g.Backend.GetAll())
+	if err != nil {
+		return err
+	}
+
+	resp, err := g.client.Post(fmt.Sprintf("http://%s/gossip", peer), "application/json", bytes.NewReader(body))
+	if err != nil {
+		g.Logger.Warn().
+			Str("peer", peer).
+			Dur("latency_ms", time.Since(start)).
+			Err(err).
+			Msg("gossip exchange failed")
+		return err
+	}
+	defer resp.Body.Close()
+
+	var remote HostData
+	if err := json.NewDecoder(resp.Body).Decode(&remote); err != nil {
+		g.Logger.Warn().
+			Str("peer", peer).
+			Dur("latency_ms", time.Since(start)).
+			Err(err).
+			Msg("gossip exchange failed")
+		return err
+	}
+
+	accepted := g.Backend.Merge(remote, peer)
+	g.Logger.Info().
+		Str("peer", peer).
+		Int("accepted", len(accepted)).
+		Dur("latency_ms", time.Since(start)).
+		Msg("gossip exchange")
+	return nil
+}
+
+// Handler serves incoming gossip pushes: it merges the peer's HostData
+// into local state and replies with our own, so a single POST acts as
+// a two-way exchange.
+func (g *Gossiper) Handler(w http.ResponseWriter, r *http.Request) {
+	var incoming HostData
+	if err := json.NewDecoder(r.Body).Decode(&incoming); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	g.Backend.Merge(incoming, r.RemoteAddr)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(g.Backend.GetAll())
+}
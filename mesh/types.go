@@ -0,0 +1,21 @@
+package mesh
+
+import "time"
+
+// Hostname is a single signed claim binding a name to a point in time.
+// The signature covers (Name, Time) and is checked, against Pubkey,
+// before a record is accepted into local state.
+type Hostname struct {
+	Name      string    `json:"name"`
+	Pubkey    string    `json:"pubkey"`
+	Signature string    `json:"signature"`
+	Time      time.Time `json:"time"`
+	Deleted   bool      `json:"deleted,omitempty"`
+}
+
+// HostData is the set of hostname claims a node knows about. It is
+// exchanged wholesale between peers during a gossip round.
+type HostData struct {
+	Timestamp time.Time  `json:"timestamp"`
+	Hostnames []Hostname `json:"hostnames"`
+}
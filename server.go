@@ -0,0 +1,78 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
+)
+
+// tlsConfig holds the flags that control how the server's transport is
+// secured.
+type tlsConfig struct {
+	Hosts        []string
+	CertCacheDir string
+	ClientCA     string
+	Insecure     bool
+}
+
+// serve starts srv according to cfg: plain HTTP when Insecure is set,
+// otherwise HTTP/2 over TLS with certificates obtained automatically
+// from Let's Encrypt for Hosts, cached under CertCacheDir. Setting
+// ClientCA turns on mutual TLS, requiring peers to present a client
+// certificate signed by that CA.
+func serve(srv *http.Server, cfg tlsConfig, logger zerolog.Logger) error {
+	if cfg.Insecure {
+		logger.Info().Str("addr", srv.Addr).Msg("starting insecure HTTP server")
+		return srv.ListenAndServe()
+	}
+
+	if len(cfg.Hosts) == 0 {
+		return fmt.Errorf("either --tls-hosts or --insecure must be set")
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(cfg.Hosts...),
+		Cache:      autocert.DirCache(cfg.CertCacheDir),
+	}
+	srv.TLSConfig = manager.TLSConfig()
+
+	if cfg.ClientCA != "" {
+		pool, err := loadClientCAs(cfg.ClientCA)
+		if err != nil {
+			return fmt.Errorf("loading client CA file: %w", err)
+		}
+		srv.TLSConfig.ClientCAs = pool
+		srv.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	if err := http2.ConfigureServer(srv, nil); err != nil {
+		return fmt.Errorf("configuring http2: %w", err)
+	}
+
+	logger.Info().
+		Str("addr", srv.Addr).
+		Strs("hosts", cfg.Hosts).
+		Bool("mtls", cfg.ClientCA != "").
+		Msg("starting TLS server")
+	return srv.ListenAndServeTLS("", "")
+}
+
+func loadClientCAs(path string) (*x509.CertPool, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(raw) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,289 @@
+// Package store persists the mesh's merged hostname registry across
+// restarts: an append-only log of accepted updates, backed by periodic
+// snapshots.
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/Lassulus/data-mesher/mesh"
+)
+
+const (
+	snapshotFileName = "snapshot.json"
+	logFileName      = "updates.log"
+)
+
+// Store is a mesh.Backend backed by an on-disk snapshot plus an
+// append-only log of updates since that snapshot. On Open it loads the
+// latest snapshot and replays the log tail on top of it; every
+// snapshotEvery accepted updates (and on Close) it writes a fresh
+// snapshot and truncates the log.
+type Store struct {
+	mu sync.Mutex
+
+	dir           string
+	verify        mesh.VerifyFunc
+	snapshotEvery int
+	fsync         bool
+	logger        zerolog.Logger
+
+	hostnames     map[string]mesh.Hostname
+	log           *os.File
+	sinceSnapshot int
+}
+
+// Open loads or creates a store rooted at dir. snapshotEvery <= 0
+// disables automatic snapshotting on Put; fsync forces every log
+// append to disk before Put returns.
+func Open(dir string, snapshotEvery int, fsync bool) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating store dir: %w", err)
+	}
+
+	s := &Store{
+		dir:           dir,
+		snapshotEvery: snapshotEvery,
+		fsync:         fsync,
+		hostnames:     make(map[string]mesh.Hostname),
+		logger:        zerolog.Nop(),
+	}
+
+	if err := s.loadSnapshot(); err != nil {
+		return nil, fmt.Errorf("loading snapshot: %w", err)
+	}
+	if err := s.replayLog(); err != nil {
+		return nil, fmt.Errorf("replaying log: %w", err)
+	}
+
+	log, err := os.OpenFile(s.logPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening log: %w", err)
+	}
+	s.log = log
+
+	return s, nil
+}
+
+// SetVerify installs the signature/trust check applied to every record
+// before it's accepted. With no verify func set, every record is
+// accepted.
+func (s *Store) SetVerify(verify mesh.VerifyFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.verify = verify
+}
+
+// SetLogger installs the logger used to report persistence failures.
+func (s *Store) SetLogger(logger zerolog.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger = logger
+}
+
+func (s *Store) snapshotPath() string { return filepath.Join(s.dir, snapshotFileName) }
+func (s *Store) logPath() string      { return filepath.Join(s.dir, logFileName) }
+
+func (s *Store) loadSnapshot() error {
+	raw, err := os.ReadFile(s.snapshotPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var hostnames []mesh.Hostname
+	if err := json.Unmarshal(raw, &hostnames); err != nil {
+		return err
+	}
+	for _, h := range hostnames {
+		s.hostnames[h.Name] = h
+	}
+	return nil
+}
+
+func (s *Store) replayLog() error {
+	f, err := os.Open(s.logPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var h mesh.Hostname
+		if err := json.Unmarshal(scanner.Bytes(), &h); err != nil {
+			return err
+		}
+		applyIfNewer(s.hostnames, h)
+	}
+	return scanner.Err()
+}
+
+// Get returns the current record for name, if any.
+func (s *Store) Get(name string) (mesh.Hostname, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.hostnames[name]
+	return h, ok
+}
+
+// Put verifies and applies a single record, appending it to the log if
+// accepted. peer identifies who submitted it, for verification
+// logging. It reports whether the record was newer/valid and thus
+// accepted.
+func (s *Store) Put(h mesh.Hostname, peer string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var existingPtr *mesh.Hostname
+	if existing, ok := s.hostnames[h.Name]; ok {
+		existingPtr = &existing
+	}
+
+	if s.verify != nil && !s.verify(h, existingPtr, peer) {
+		return false, nil
+	}
+	if !applyIfNewer(s.hostnames, h) {
+		return false, nil
+	}
+
+	if err := s.appendLog(h); err != nil {
+		return false, err
+	}
+
+	s.sinceSnapshot++
+	if s.snapshotEvery > 0 && s.sinceSnapshot >= s.snapshotEvery {
+		if err := s.snapshotLocked(); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// Merge applies every record in incoming and returns those accepted.
+// peer identifies who sent incoming. It satisfies mesh.Backend so a
+// Store can drive a mesh.Gossiper directly.
+func (s *Store) Merge(incoming mesh.HostData, peer string) []mesh.Hostname {
+	var accepted []mesh.Hostname
+	for _, h := range incoming.Hostnames {
+		ok, err := s.Put(h, peer)
+		if err != nil {
+			s.logger.Error().Str("name", h.Name).Err(err).Msg("failed to persist record")
+			continue
+		}
+		if ok {
+			accepted = append(accepted, h)
+		}
+	}
+	return accepted
+}
+
+// GetAll returns the full hostname set as a HostData. It performs no
+// disk I/O, so it's the right call for a read path exercised on every
+// request: gossip exchanges and GET /names both use it. It satisfies
+// mesh.Backend so the current state can be sent to peers.
+func (s *Store) GetAll() mesh.HostData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.collectLocked()
+}
+
+// Snapshot persists the full hostname set to disk and truncates the
+// update log, returning the data written. Put triggers it automatically
+// every snapshotEvery accepted updates; call it directly for an
+// explicit, out-of-band snapshot. Write failures are logged rather
+// than returned, since callers only care about the current data.
+func (s *Store) Snapshot() mesh.HostData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := s.collectLocked()
+	if err := s.snapshotLocked(); err != nil {
+		s.logger.Error().Err(err).Msg("failed to write snapshot")
+	}
+	return data
+}
+
+func (s *Store) snapshotLocked() error {
+	return s.writeSnapshotLocked(s.collectLocked())
+}
+
+// Close writes a final snapshot and closes the underlying log file.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.snapshotLocked(); err != nil {
+		return err
+	}
+	return s.log.Close()
+}
+
+func (s *Store) collectLocked() mesh.HostData {
+	hostnames := make([]mesh.Hostname, 0, len(s.hostnames))
+	for _, h := range s.hostnames {
+		hostnames = append(hostnames, h)
+	}
+	return mesh.HostData{Timestamp: time.Now(), Hostnames: hostnames}
+}
+
+func (s *Store) writeSnapshotLocked(data mesh.HostData) error {
+	raw, err := json.Marshal(data.Hostnames)
+	if err != nil {
+		return err
+	}
+
+	tmp := s.snapshotPath() + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.snapshotPath()); err != nil {
+		return err
+	}
+
+	if err := s.log.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := s.log.Seek(0, 0); err != nil {
+		return err
+	}
+	s.sinceSnapshot = 0
+	return nil
+}
+
+func (s *Store) appendLog(h mesh.Hostname) error {
+	raw, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	if _, err := s.log.Write(append(raw, '\n')); err != nil {
+		return err
+	}
+	if s.fsync {
+		return s.log.Sync()
+	}
+	return nil
+}
+
+// applyIfNewer keeps the newest (highest Time) record per name,
+// reporting whether h replaced what was there.
+func applyIfNewer(hostnames map[string]mesh.Hostname, h mesh.Hostname) bool {
+	if existing, ok := hostnames[h.Name]; ok && !h.Time.After(existing.Time) {
+		return false
+	}
+	hostnames[h.Name] = h
+	return true
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Lassulus/data-mesher/mesh"
+)
+
+func TestPutPersistsAcrossReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	s1, err := Open(dir, 0, false)
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+
+	h := mesh.Hostname{Name: "alice", Pubkey: "pub", Signature: "sig", Time: time.Now()}
+	if ok, err := s1.Put(h, "test"); err != nil || !ok {
+		t.Fatalf("Put() = %v, %v, want true, <nil>", ok, err)
+	}
+	if err := s1.Close(); err != nil {
+		t.Fatalf("Close: %s", err)
+	}
+
+	s2, err := Open(dir, 0, false)
+	if err != nil {
+		t.Fatalf("reopening: %s", err)
+	}
+	got, ok := s2.Get("alice")
+	if !ok {
+		t.Fatal("expected alice to survive reopen via the snapshot written by Close")
+	}
+	if got.Signature != h.Signature {
+		t.Errorf("got signature %q, want %q", got.Signature, h.Signature)
+	}
+}
+
+func TestPutReplaysLogTailWithoutSnapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	s1, err := Open(dir, 0, false)
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+
+	h := mesh.Hostname{Name: "bob", Pubkey: "pub", Signature: "sig", Time: time.Now()}
+	if _, err := s1.Put(h, "test"); err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+
+	// Close the log file directly, without Close(), so no snapshot is
+	// written -- this simulates a crash and forces recovery to come
+	// from replaying the log tail.
+	if err := s1.log.Close(); err != nil {
+		t.Fatalf("closing log: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, snapshotFileName)); !os.IsNotExist(err) {
+		t.Fatalf("expected no snapshot file yet, stat error: %v", err)
+	}
+
+	s2, err := Open(dir, 0, false)
+	if err != nil {
+		t.Fatalf("reopening: %s", err)
+	}
+	if _, ok := s2.Get("bob"); !ok {
+		t.Fatal("expected bob to be recovered by replaying the log tail")
+	}
+}
+
+func TestPutRejectsStaleRecord(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, 0, false)
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+
+	now := time.Now()
+	newer := mesh.Hostname{Name: "alice", Pubkey: "pub", Signature: "new", Time: now}
+	older := mesh.Hostname{Name: "alice", Pubkey: "pub", Signature: "old", Time: now.Add(-time.Minute)}
+
+	if ok, err := s.Put(newer, "test"); err != nil || !ok {
+		t.Fatalf("Put(newer) = %v, %v, want true, <nil>", ok, err)
+	}
+	if ok, err := s.Put(older, "test"); err != nil || ok {
+		t.Fatalf("Put(older) = %v, %v, want false, <nil>", ok, err)
+	}
+}
+
+func TestGetAllDoesNotWriteASnapshot(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, 0, false)
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+	if _, err := s.Put(mesh.Hostname{Name: "alice", Pubkey: "pub", Signature: "sig", Time: time.Now()}, "test"); err != nil {
+		t.Fatalf("Put: %s", err)
+	}
+
+	s.GetAll()
+
+	if _, err := os.Stat(filepath.Join(dir, snapshotFileName)); !os.IsNotExist(err) {
+		t.Fatalf("expected GetAll to perform no disk I/O, stat error: %v", err)
+	}
+}